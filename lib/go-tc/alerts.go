@@ -0,0 +1,46 @@
+package tc
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// Alert levels, used both as the `level` field of an Alert and as an
+// argument to CreateAlerts.
+const (
+	SuccessLevel = "success"
+	InfoLevel    = "info"
+	WarnLevel    = "warning"
+	ErrorLevel   = "error"
+)
+
+// Alert is a single user-facing message returned alongside an API response.
+type Alert struct {
+	Level string `json:"level"`
+	Text  string `json:"text"`
+}
+
+// Alerts is the `alerts` array every Traffic Ops API response carries.
+type Alerts struct {
+	Alerts []Alert `json:"alerts,omitempty"`
+}
+
+// CreateAlerts builds an Alerts containing a single Alert at the given
+// level.
+func CreateAlerts(level string, msg string) Alerts {
+	return Alerts{Alerts: []Alert{{Level: level, Text: msg}}}
+}
@@ -0,0 +1,40 @@
+package tc
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// DeliveryServiceServer is a single delivery service/server association.
+type DeliveryServiceServer struct {
+	Server          int `json:"server"`
+	DeliveryService int `json:"deliveryService"`
+}
+
+// DeliveryServiceServerResponse is the response body of
+// GET /deliveryserviceserver.
+type DeliveryServiceServerResponse struct {
+	Response []DeliveryServiceServer `json:"response"`
+}
+
+// DSServerIDs is the request and response body for assigning a set of
+// servers to a delivery service.
+type DSServerIDs struct {
+	DeliveryServiceID *int  `json:"dsId"`
+	ServerIDs         []int `json:"servers"`
+	Replace           *bool `json:"replace"`
+}
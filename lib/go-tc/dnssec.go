@@ -0,0 +1,63 @@
+package tc
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// DNSSECKey is a single DNSSEC key (a KSK or a ZSK) as stored for a CDN or
+// delivery service.
+type DNSSECKey struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	TTLSeconds uint64 `json:"ttlSeconds"`
+	// Algorithm is the IANA DNSSEC algorithm number the key was generated
+	// under (e.g. 8 for RSASHA256, 13 for ECDSAP256SHA256). It's omitted
+	// from keys persisted before ECDSAP256SHA256 support was added; a zero
+	// value means RSASHA256.
+	Algorithm          uint8  `json:"algorithm,omitempty"`
+	Public             string `json:"public"`
+	Private            string `json:"private"`
+	InceptionDateUnix  int64  `json:"inceptionDateUnix"`
+	ExpirationDateUnix int64  `json:"expirationDateUnix"`
+}
+
+// DNSSECKeySet is the KSKs and ZSKs for a single CDN or delivery service.
+// The first entry in each slice is the active key; any others are kept
+// around until their own expiration so in-flight resolver caches still
+// validate.
+type DNSSECKeySet struct {
+	ZSK []DNSSECKey `json:"zsk"`
+	KSK []DNSSECKey `json:"ksk"`
+}
+
+// DNSSECKeys maps a CDN name, or one of its delivery services' xml_ids, to
+// its DNSSECKeySet.
+type DNSSECKeys map[string]DNSSECKeySet
+
+// CDNDNSSECGenerateReq is the request body for CreateDNSSECKeys.
+type CDNDNSSECGenerateReq struct {
+	Key               *string `json:"key"`
+	Name              *string `json:"name"`
+	TTL               *uint64 `json:"ttl"`
+	KSKExpirationDays *uint64 `json:"kskExpirationDays"`
+	ZSKExpirationDays *uint64 `json:"zskExpirationDays"`
+	EffectiveDateUnix *int64  `json:"effectiveDate,omitempty"`
+	// Algorithm selects the DNSSEC algorithm new keys are generated with
+	// ("RSASHA256" or "ECDSAP256SHA256"); empty defaults to RSASHA256.
+	Algorithm string `json:"algorithm,omitempty"`
+}
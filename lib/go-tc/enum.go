@@ -0,0 +1,76 @@
+package tc
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// CDNName is the name of a CDN.
+type CDNName string
+
+// DeliveryServiceName is the xml_id of a delivery service.
+type DeliveryServiceName string
+
+// ApiErrorType classifies an error returned by the API so handlers can
+// decide what HTTP status and alert level to respond with.
+type ApiErrorType int
+
+const (
+	// SystemError indicates an error caused by Traffic Ops itself (e.g. a
+	// failed query), rather than anything the client did wrong.
+	SystemError ApiErrorType = iota
+	// DataConflictError indicates the request conflicts with existing
+	// data, e.g. a uniqueness violation.
+	DataConflictError
+)
+
+// DSType is the routing type of a delivery service.
+type DSType string
+
+// Delivery service types known to Traffic Ops.
+const (
+	DSTypeHTTP    = DSType("HTTP")
+	DSTypeDNS     = DSType("DNS")
+	DSTypeAnyMap  = DSType("ANY_MAP")
+	DSTypeInvalid = DSType("")
+)
+
+// DSTypeFromString maps a delivery service type's database name to a
+// DSType, returning DSTypeInvalid if s isn't recognized.
+func DSTypeFromString(s string) DSType {
+	switch {
+	case s == string(DSTypeAnyMap):
+		return DSTypeAnyMap
+	case len(s) >= len("HTTP") && s[:len("HTTP")] == "HTTP":
+		return DSTypeHTTP
+	case len(s) >= len("DNS") && s[:len("DNS")] == "DNS":
+		return DSTypeDNS
+	default:
+		return DSTypeInvalid
+	}
+}
+
+// IsHTTP returns whether t is one of the HTTP-routed delivery service
+// types.
+func (t DSType) IsHTTP() bool {
+	return t == DSTypeHTTP
+}
+
+// IsDNS returns whether t is one of the DNS-routed delivery service types.
+func (t DSType) IsDNS() bool {
+	return t == DSTypeDNS
+}
@@ -0,0 +1,33 @@
+package tc
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+// SSLKeys is the certificate, CSR, and private key for a delivery service's
+// SSL/TLS certificate, for a single version.
+type SSLKeys struct {
+	DeliveryService string `json:"deliveryservice"`
+	CDN             string `json:"cdn"`
+	Version         int    `json:"version"`
+	Certificate     struct {
+		Crt string `json:"crt"`
+		Key string `json:"key"`
+		CSR string `json:"csr"`
+	} `json:"certificate"`
+}
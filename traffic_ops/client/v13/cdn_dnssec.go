@@ -0,0 +1,30 @@
+/*
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+   http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package v13
+
+import (
+	"github.com/apache/trafficcontrol/lib/go-tc"
+)
+
+// RefreshCDNDNSSECKeys starts an asynchronous refresh of any DNSSEC keys
+// nearing expiration. cdnName only forms the request path: the server
+// refreshes the keys of every CDN (and their delivery services).
+func (to *Session) RefreshCDNDNSSECKeys(cdnName string) (tc.Alerts, ReqInf, error) {
+	path := apiBase + `/cdns/` + cdnName + `/dnsseckeys/refresh`
+	alerts := tc.Alerts{}
+	reqInf, err := post(to, path, nil, &alerts)
+	return alerts, reqInf, err
+}
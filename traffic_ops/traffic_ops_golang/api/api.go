@@ -0,0 +1,244 @@
+package api
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/apache/trafficcontrol/lib/go-log"
+	"github.com/apache/trafficcontrol/lib/go-tc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/config"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/keystore"
+
+	"github.com/gorilla/mux"
+	"github.com/jmoiron/sqlx"
+)
+
+// ApiChange is the change log level recorded for API-driven changes, as
+// opposed to ones made directly against the database.
+const ApiChange = "APICHANGE"
+
+// db and cfg are set once at server startup by Init, and read by every
+// request's NewInfo afterward. Request handling itself is per-request
+// (each APIInfo gets its own tx), so this package-level state never
+// changes concurrently with request handling.
+var (
+	db  *sqlx.DB
+	cfg *config.Config
+)
+
+// Init wires the server's shared database handle and config into this
+// package, so NewInfo can build a request-scoped APIInfo from them. It must
+// be called once, before the server starts accepting requests.
+func Init(theDB *sqlx.DB, theCfg *config.Config) {
+	db = theDB
+	cfg = theCfg
+}
+
+// GetDB returns the server's shared database handle, as set by Init. It
+// returns an error if Init hasn't been called yet, so a caller that needs a
+// tx outliving the request (e.g. a handler kicking off background work)
+// can open one of its own.
+func GetDB(r *http.Request) (*sqlx.DB, error) {
+	if db == nil {
+		return nil, errors.New("database is not initialized")
+	}
+	return db, nil
+}
+
+// userInfoContextKey is the request context key CurrentUser is attached to
+// by the authentication middleware that runs ahead of every handler.
+type userInfoContextKey struct{}
+
+// CurrentUser returns the authenticated user attached to the request's
+// context, or the zero tc.UserInfo if the context has none (e.g. in tests
+// that construct requests directly).
+func CurrentUser(r *http.Request) tc.UserInfo {
+	user, ok := r.Context().Value(userInfoContextKey{}).(tc.UserInfo)
+	if !ok {
+		return tc.UserInfo{}
+	}
+	return user
+}
+
+// WithCurrentUser returns a copy of r with user attached to its context,
+// for use by authentication middleware and tests.
+func WithCurrentUser(r *http.Request, user tc.UserInfo) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), userInfoContextKey{}, user))
+}
+
+// APIInfo carries everything a handler needs to serve a single request: its
+// own transaction, its path parameters, the authenticated user, the server
+// config, and a key Store built from that config.
+type APIInfo struct {
+	Tx       *sqlx.Tx
+	Params   map[string]string
+	User     tc.UserInfo
+	Config   *config.Config
+	CommitTx *bool
+	Store    keystore.Store
+}
+
+// NewInfo begins a new request-scoped APIInfo: it opens a tx against the
+// shared database, collects the path parameters named in requiredParams
+// and intParams (returning a user error if any are missing or, for
+// intParams, not an integer), and builds a Store from the server config.
+// The caller must call Close (usually via defer) when it's done with the
+// returned APIInfo, which commits the tx if *APIInfo.CommitTx was set true
+// and rolls it back otherwise.
+func NewInfo(r *http.Request, requiredParams []string, intParams []string) (*APIInfo, error, error, int) {
+	if db == nil || cfg == nil {
+		return nil, nil, errors.New("api package is not initialized"), http.StatusInternalServerError
+	}
+
+	params := mux.Vars(r)
+	for _, name := range requiredParams {
+		if _, ok := params[name]; !ok {
+			return nil, errors.New("missing required parameter '" + name + "'"), nil, http.StatusBadRequest
+		}
+	}
+	for _, name := range intParams {
+		val, ok := params[name]
+		if !ok {
+			return nil, errors.New("missing required parameter '" + name + "'"), nil, http.StatusBadRequest
+		}
+		if _, err := parseInt(val); err != nil {
+			return nil, errors.New("parameter '" + name + "' must be an integer"), nil, http.StatusBadRequest
+		}
+	}
+
+	tx, err := db.BeginTxx(r.Context(), nil)
+	if err != nil {
+		return nil, nil, errors.New("beginning transaction: " + err.Error()), http.StatusInternalServerError
+	}
+
+	store, err := keystore.NewFromConfig(cfg)
+	if err != nil {
+		tx.Rollback()
+		return nil, nil, errors.New("building key store: " + err.Error()), http.StatusInternalServerError
+	}
+
+	commitTx := false
+	return &APIInfo{
+		Tx:       tx,
+		Params:   params,
+		User:     CurrentUser(r),
+		Config:   cfg,
+		CommitTx: &commitTx,
+		Store:    store,
+	}, nil, nil, http.StatusOK
+}
+
+// Close commits inf's tx if *inf.CommitTx was set true, otherwise rolls it
+// back. Handlers call this via defer.
+func (inf *APIInfo) Close() {
+	if inf == nil || inf.Tx == nil {
+		return
+	}
+	if inf.CommitTx != nil && *inf.CommitTx {
+		if err := inf.Tx.Commit(); err != nil {
+			log.Errorln("committing transaction: " + err.Error())
+		}
+		return
+	}
+	if err := inf.Tx.Rollback(); err != nil && err != sql.ErrTxDone {
+		log.Errorln("rolling back transaction: " + err.Error())
+	}
+}
+
+// parseInt parses s as an unsigned decimal integer (digits only, no sign),
+// so NewInfo doesn't need to import strconv just for this one check.
+func parseInt(s string) (int, error) {
+	n := 0
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return 0, errors.New("not an integer")
+		}
+		n = n*10 + int(r-'0')
+	}
+	return n, nil
+}
+
+// HandleErr writes an error response: userErr's message is sent to the
+// client, while sysErr (if any) is only logged, since it may carry details
+// (e.g. a raw SQL error) that shouldn't be exposed.
+func HandleErr(w http.ResponseWriter, r *http.Request, statusCode int, userErr error, sysErr error) {
+	if sysErr != nil {
+		log.Errorln(r.Method + " " + r.URL.Path + ": " + sysErr.Error())
+	}
+	if userErr == nil {
+		userErr = errors.New(http.StatusText(statusCode))
+	}
+	WriteAlerts(w, r, statusCode, tc.CreateAlerts(tc.ErrorLevel, userErr.Error()))
+}
+
+// WriteResp writes v as a JSON response body, wrapped in the standard
+// `{"response": ...}` envelope, with a 200 status.
+func WriteResp(w http.ResponseWriter, r *http.Request, v interface{}) {
+	resp := struct {
+		Response interface{} `json:"response"`
+	}{Response: v}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// WriteAlerts writes alerts as the JSON response body, with the given
+// status.
+func WriteAlerts(w http.ResponseWriter, r *http.Request, statusCode int, alerts tc.Alerts) {
+	writeJSON(w, statusCode, alerts)
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Errorln("marshaling response: " + err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// Parse reads body as JSON into v. tx is accepted (and unused here) so
+// callers that need to validate a parsed request against the database can
+// be given the same signature without Parse itself needing to change.
+func Parse(body io.Reader, tx *sql.Tx, v interface{}) error {
+	return json.NewDecoder(body).Decode(v)
+}
+
+// CreateChangeLogRawTx records a change log entry for an API-driven change,
+// using tx rather than opening a new one, so it participates in the
+// caller's existing transaction.
+func CreateChangeLogRawTx(level string, message string, user tc.UserInfo, tx *sql.Tx) {
+	if _, err := tx.Exec(
+		`INSERT INTO log (level, message, tm_user) VALUES ($1, $2, $3)`,
+		level, message, user.UserName,
+	); err != nil {
+		log.Errorln("writing change log entry: " + err.Error())
+	}
+}
@@ -20,9 +20,13 @@ package cdn
  */
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/trafficcontrol/lib/go-log"
@@ -30,12 +34,299 @@ import (
 	"github.com/apache/trafficcontrol/lib/go-util"
 	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/api"
 	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/config"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/dbhelpers"
 	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/deliveryservice"
-	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/riaksvc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/keystore"
+
+	"github.com/jmoiron/sqlx"
 )
 
 const CDNDNSSECKeyType = "dnssec"
 const DNSSECStatusExisting = "existing"
+const DNSSECStatusNew = "new"
+
+// DNSSEC algorithm numbers, per IANA's "Domain Name System Security (DNSSEC)
+// Algorithm Numbers" registry. RSASHA256 remains the default for backward
+// compatibility with key sets stored before ECDSAP256SHA256 was supported.
+const (
+	DNSSECAlgorithmRSASHA256       = uint8(8)
+	DNSSECAlgorithmECDSAP256SHA256 = uint8(13)
+)
+
+const DNSSECAlgorithmNameRSASHA256 = "RSASHA256"
+const DNSSECAlgorithmNameECDSAP256SHA256 = "ECDSAP256SHA256"
+
+// dnssecAlgorithmFromName maps the request-facing algorithm name to its
+// IANA algorithm number, defaulting to RSASHA256 if name is empty.
+func dnssecAlgorithmFromName(name string) (uint8, error) {
+	switch name {
+	case "", DNSSECAlgorithmNameRSASHA256:
+		return DNSSECAlgorithmRSASHA256, nil
+	case DNSSECAlgorithmNameECDSAP256SHA256:
+		return DNSSECAlgorithmECDSAP256SHA256, nil
+	default:
+		return 0, errors.New("unknown DNSSEC algorithm '" + name + "'")
+	}
+}
+
+// dnssecKeyAlgorithm returns the algorithm of a previously-stored key,
+// defaulting to RSASHA256 for keys persisted before the Algorithm field
+// existed.
+func dnssecKeyAlgorithm(key tc.DNSSECKey) uint8 {
+	if key.Algorithm == 0 {
+		return DNSSECAlgorithmRSASHA256
+	}
+	return key.Algorithm
+}
+
+// DefaultDNSSECRefreshWindowDays is how far before a key's expiration a
+// refresh will regenerate it, if the `dnssec.refresh.window.days` global
+// parameter isn't set.
+const DefaultDNSSECRefreshWindowDays = 10
+
+// dnssecRefreshRunning guards against more than one DNSSEC key refresh
+// running at a time across the CDN, since each refresh touches every CDN's
+// keys in the configured key store and refreshes them in place.
+var dnssecRefreshRunning int32
+
+// RefreshDNSSECKeys scans the DNSSEC keys of every CDN and, for any KSK or
+// ZSK nearing its expiration, generates and stores a replacement. The
+// refresh runs in the background; the handler returns as soon as it's
+// started so a single request doesn't have to stay open for however long a
+// full-CDN-list refresh takes.
+func RefreshDNSSECKeys(w http.ResponseWriter, r *http.Request) {
+	inf, userErr, sysErr, errCode := api.NewInfo(r, nil, nil)
+	if userErr != nil || sysErr != nil {
+		api.HandleErr(w, r, errCode, userErr, sysErr)
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&dnssecRefreshRunning, 0, 1) {
+		inf.Close()
+		api.WriteAlerts(w, r, http.StatusOK, tc.CreateAlerts(tc.InfoLevel, "a DNSSEC keys refresh is already running, try again later"))
+		return
+	}
+
+	db, err := api.GetDB(r)
+	if err != nil {
+		atomic.StoreInt32(&dnssecRefreshRunning, 0)
+		inf.Close()
+		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("getting db handle: "+err.Error()))
+		return
+	}
+	cfg := inf.Config
+	user := inf.User
+	// This request's own tx (and the context it's bound to) won't outlive
+	// the handler, so the background refresh below can't use it - it opens
+	// its own tx against context.Background() instead.
+	inf.Close()
+
+	go refreshAllDNSSECKeys(db, cfg, user)
+
+	api.WriteAlerts(w, r, http.StatusAccepted, tc.CreateAlerts(tc.SuccessLevel, "Starting DNSSEC keys refresh in the background"))
+}
+
+// refreshAllDNSSECKeys does the actual work for RefreshDNSSECKeys. Since the
+// HTTP handler that started it has already responded (and its request-scoped
+// tx is gone), each CDN it refreshes is done in its own transaction against
+// context.Background(), committed before moving on to the next CDN - so a
+// crash or failure partway through the install's CDN list only loses the one
+// CDN in flight, not every CDN refreshed before it.
+func refreshAllDNSSECKeys(db *sqlx.DB, cfg *config.Config, user tc.UserInfo) {
+	defer atomic.StoreInt32(&dnssecRefreshRunning, 0)
+
+	store, err := keystore.NewFromConfig(cfg)
+	if err != nil {
+		log.Errorln("refreshing DNSSEC keys: building key store: " + err.Error())
+		return
+	}
+
+	cdns, refreshWindow, err := getCDNNamesAndRefreshWindow(db)
+	if err != nil {
+		log.Errorln("refreshing DNSSEC keys: " + err.Error())
+		return
+	}
+
+	refreshed := []string{}
+	failed := []string{}
+	now := time.Now().Unix()
+	for _, cdnName := range cdns {
+		didRefresh, err := refreshDNSSECKeysForCDNTx(db, store, cdnName, now, refreshWindow, user)
+		if err != nil {
+			log.Errorln("refreshing DNSSEC keys for CDN '" + cdnName + "': " + err.Error())
+			failed = append(failed, cdnName) // keep trying the remaining CDNs rather than aborting the whole refresh
+		}
+		if didRefresh {
+			refreshed = append(refreshed, cdnName)
+		}
+	}
+
+	log.Infoln("DNSSEC keys refresh complete. Refreshed: " + joinOrNone(refreshed) + ". Failed: " + joinOrNone(failed) + ".")
+}
+
+// getCDNNamesAndRefreshWindow opens its own short-lived tx to read the
+// install's CDN names and its configured refresh window, since neither
+// needs to be read inside the same tx as the refresh work itself.
+func getCDNNamesAndRefreshWindow(db *sqlx.DB) ([]string, time.Duration, error) {
+	dbTx, err := db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return nil, 0, errors.New("beginning transaction: " + err.Error())
+	}
+	commitTx := false
+	defer dbhelpers.FinishTxX(dbTx, &commitTx)
+	tx := dbTx.Tx
+
+	refreshWindow := getDNSSECRefreshWindow(tx)
+	cdns, err := getCDNNames(tx)
+	if err != nil {
+		return nil, 0, errors.New("getting CDN names: " + err.Error())
+	}
+	commitTx = true
+	return cdns, refreshWindow, nil
+}
+
+// refreshDNSSECKeysForCDNTx opens its own tx for a single CDN's refresh and
+// commits it (recording a change log entry if any key was refreshed) before
+// returning, so this CDN's
+// work is durable independent of whatever happens to the CDNs refreshed
+// after it.
+func refreshDNSSECKeysForCDNTx(db *sqlx.DB, store keystore.Store, cdnName string, now int64, refreshWindow time.Duration, user tc.UserInfo) (bool, error) {
+	dbTx, err := db.BeginTxx(context.Background(), nil)
+	if err != nil {
+		return false, errors.New("beginning transaction: " + err.Error())
+	}
+	commitTx := false
+	defer dbhelpers.FinishTxX(dbTx, &commitTx)
+	tx := dbTx.Tx
+
+	didRefresh, err := refreshDNSSECKeysForCDN(tx, store, cdnName, now, refreshWindow)
+	if didRefresh {
+		api.CreateChangeLogRawTx(api.ApiChange, "Refreshed DNSSEC keys for CDN "+cdnName, user, tx)
+	}
+	commitTx = true // persist whatever succeeded even if refreshDNSSECKeysForCDN also returned a partial-failure error
+	return didRefresh, err
+}
+
+func joinOrNone(ss []string) string {
+	if len(ss) == 0 {
+		return "none"
+	}
+	joined := ss[0]
+	for _, s := range ss[1:] {
+		joined += ", " + s
+	}
+	return joined
+}
+
+// getDNSSECRefreshWindow returns how far before expiration a key should be
+// refreshed, as a duration, from the `dnssec.refresh.window.days` global
+// parameter, or DefaultDNSSECRefreshWindowDays if it isn't set or invalid.
+func getDNSSECRefreshWindow(tx *sql.Tx) time.Duration {
+	days := DefaultDNSSECRefreshWindowDays
+	if val, exists, err := dbhelpers.GetGlobalParam(tx, "dnssec.refresh.window.days"); err != nil {
+		log.Errorln("getting dnssec.refresh.window.days parameter, using default: " + err.Error())
+	} else if exists {
+		if parsed, err := strconv.Atoi(val); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * time.Hour * 24
+}
+
+func getCDNNames(tx *sql.Tx) ([]string, error) {
+	rows, err := tx.Query(`SELECT name FROM cdn`)
+	if err != nil {
+		return nil, errors.New("querying cdn names: " + err.Error())
+	}
+	defer rows.Close()
+	names := []string{}
+	for rows.Next() {
+		name := ""
+		if err := rows.Scan(&name); err != nil {
+			return nil, errors.New("scanning cdn name: " + err.Error())
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// refreshDNSSECKeysForCDN refreshes any KSK or ZSK of the given CDN or its
+// delivery services whose expiration falls within refreshWindow of now. A
+// failure to refresh one DS's keys doesn't stop the rest from being tried -
+// whatever succeeds is persisted, and the first return value reports
+// whether anything did. Any per-DS failures are joined into the error.
+func refreshDNSSECKeysForCDN(tx *sql.Tx, store keystore.Store, cdnName string, now int64, refreshWindow time.Duration) (bool, error) {
+	keys, keysExist, err := store.GetDNSSECKeys(tx, cdnName)
+	if err != nil {
+		return false, errors.New("getting dnssec keys: " + err.Error())
+	}
+	if !keysExist {
+		return false, nil
+	}
+
+	didRefresh := false
+	failures := []string{}
+	for name, keySet := range keys {
+		newZSK, zskRefreshed, zskErr := refreshKeysNearingExpiration(keySet.ZSK, now, refreshWindow)
+		if zskErr != nil {
+			failures = append(failures, "zsk for '"+name+"': "+zskErr.Error())
+			newZSK, zskRefreshed = keySet.ZSK, false
+		}
+		newKSK, kskRefreshed, kskErr := refreshKeysNearingExpiration(keySet.KSK, now, refreshWindow)
+		if kskErr != nil {
+			failures = append(failures, "ksk for '"+name+"': "+kskErr.Error())
+			newKSK, kskRefreshed = keySet.KSK, false
+		}
+		if zskRefreshed || kskRefreshed {
+			keys[name] = tc.DNSSECKeySet{ZSK: newZSK, KSK: newKSK}
+			didRefresh = true
+		}
+	}
+
+	if didRefresh {
+		if err := store.PutDNSSECKeys(tx, cdnName, keys); err != nil {
+			return false, errors.New("putting refreshed dnssec keys: " + err.Error())
+		}
+	}
+	if len(failures) > 0 {
+		return didRefresh, errors.New(strings.Join(failures, "; "))
+	}
+	return didRefresh, nil
+}
+
+// refreshKeysNearingExpiration replaces the current (first) key in keys
+// with a newly generated one if it expires within refreshWindow of now,
+// demoting the current key to status "existing" and marking the new key
+// "new". It returns the resulting key list and whether a refresh happened.
+func refreshKeysNearingExpiration(keys []tc.DNSSECKey, now int64, refreshWindow time.Duration) ([]tc.DNSSECKey, bool, error) {
+	if len(keys) == 0 {
+		return keys, false, nil
+	}
+	current := keys[0]
+	if current.ExpirationDateUnix > now+int64(refreshWindow/time.Second) {
+		return keys, false, nil // not yet within the refresh window
+	}
+
+	exp := current.ExpirationDateUnix - current.InceptionDateUnix
+	algorithm := dnssecKeyAlgorithm(current)
+	public, private, err := generateDNSSECKeyPair(algorithm)
+	if err != nil {
+		return nil, false, errors.New("generating replacement key: " + err.Error())
+	}
+	newKey := tc.DNSSECKey{
+		Name:               current.Name,
+		Status:             DNSSECStatusNew,
+		TTLSeconds:         current.TTLSeconds,
+		Algorithm:          algorithm,
+		Public:             public,
+		Private:            private,
+		InceptionDateUnix:  now,
+		ExpirationDateUnix: now + exp,
+	}
+
+	current.Status = DNSSECStatusExisting
+	return append([]tc.DNSSECKey{newKey, current}, keys[1:]...), true, nil
+}
 
 func CreateDNSSECKeys(w http.ResponseWriter, r *http.Request) {
 	inf, userErr, sysErr, errCode := api.NewInfo(r, nil, nil)
@@ -53,8 +344,13 @@ func CreateDNSSECKeys(w http.ResponseWriter, r *http.Request) {
 	if req.EffectiveDateUnix == nil {
 		req.EffectiveDateUnix = util.Int64Ptr(time.Now().Unix())
 	}
+	algorithm, err := dnssecAlgorithmFromName(req.Algorithm)
+	if err != nil {
+		api.HandleErr(w, r, http.StatusBadRequest, err, nil)
+		return
+	}
 	cdnName := *req.Key
-	if err := generateStoreDNSSECKeys(inf.Tx.Tx, inf.Config, cdnName, *req.TTL, *req.KSKExpirationDays, *req.ZSKExpirationDays, *req.EffectiveDateUnix); err != nil {
+	if err := generateStoreDNSSECKeys(inf.Tx.Tx, inf.Store, cdnName, *req.TTL, *req.KSKExpirationDays, *req.ZSKExpirationDays, *req.EffectiveDateUnix, algorithm); err != nil {
 		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("generating and storing DNSSEC CDN keys: "+err.Error()))
 		return
 	}
@@ -64,12 +360,13 @@ func CreateDNSSECKeys(w http.ResponseWriter, r *http.Request) {
 
 func generateStoreDNSSECKeys(
 	tx *sql.Tx,
-	cfg *config.Config,
+	store keystore.Store,
 	cdnName string,
 	ttlSeconds uint64,
 	kExpDays uint64,
 	zExpDays uint64,
 	effectiveDateUnix int64,
+	algorithm uint8,
 ) error {
 
 	zExp := time.Duration(zExpDays) * time.Hour * 24
@@ -78,7 +375,7 @@ func generateStoreDNSSECKeys(
 
 	newKeys := tc.DNSSECKeys{}
 	// (tc.DNSSECKeys, bool, error) {
-	oldKeys, oldKeysExist, err := riaksvc.GetDNSSECKeys(cdnName, tx, cfg.RiakAuthOptions)
+	oldKeys, oldKeysExist, err := store.GetDNSSECKeys(tx, cdnName)
 	if err != nil {
 		return errors.New("getting old dnssec keys: " + err.Error())
 	}
@@ -129,14 +426,14 @@ func generateStoreDNSSECKeys(
 		exampleURLs := deliveryservice.MakeExampleURLs(ds.Protocol, ds.Type, ds.RoutingName, matchlist, cdnDomain)
 		log.Infoln("Creating keys for " + ds.Name)
 		overrideTTL := true
-		dsKeys, err := deliveryservice.CreateDNSSECKeys(tx, cfg, ds.Name, exampleURLs, cdnKeys, kExp, zExp, ttl, overrideTTL)
+		dsKeys, err := deliveryservice.CreateDNSSECKeys(tx, ds.Name, exampleURLs, cdnKeys, kExp, zExp, ttl, overrideTTL, algorithm)
 		if err != nil {
 			return errors.New("creating delivery service DNSSEC keys: " + err.Error())
 		}
 		newKeys[ds.Name] = dsKeys
 	}
-	if err := riaksvc.PutDNSSECKeys(newKeys, cdnName, tx, cfg.RiakAuthOptions); err != nil {
-		return errors.New("putting Riak DNSSEC CDN keys: " + err.Error())
+	if err := store.PutDNSSECKeys(tx, cdnName, newKeys); err != nil {
+		return errors.New("putting DNSSEC CDN keys: " + err.Error())
 	}
 	return nil
 }
@@ -190,18 +487,7 @@ func DeleteDNSSECKeys(w http.ResponseWriter, r *http.Request) {
 
 	key := inf.Params["name"]
 
-	riakCluster, err := riaksvc.GetRiakClusterTx(inf.Tx.Tx, inf.Config.RiakAuthOptions)
-	if err != nil {
-		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("getting riak cluster: "+err.Error()))
-		return
-	}
-	if err := riakCluster.Start(); err != nil {
-		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("starting riak cluster: "+err.Error()))
-		return
-	}
-	defer riaksvc.StopCluster(riakCluster)
-
-	if err := riaksvc.DeleteObject(key, CDNDNSSECKeyType, riakCluster); err != nil {
+	if err := inf.Store.DeleteDNSSECKeys(inf.Tx.Tx, key); err != nil {
 		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("deleting cdn dnssec keys: "+err.Error()))
 		return
 	}
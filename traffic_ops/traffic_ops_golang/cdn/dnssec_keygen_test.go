@@ -0,0 +1,114 @@
+package cdn
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+)
+
+func TestGenerateDNSSECKeyPair(t *testing.T) {
+	cases := []struct {
+		name      string
+		algorithm uint8
+		wantErr   bool
+	}{
+		{name: "rsasha256", algorithm: DNSSECAlgorithmRSASHA256},
+		{name: "ecdsap256sha256", algorithm: DNSSECAlgorithmECDSAP256SHA256},
+		{name: "unsupported algorithm", algorithm: 255, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			public, private, err := generateDNSSECKeyPair(c.algorithm)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if public == "" || private == "" {
+				t.Fatalf("generateDNSSECKeyPair(%d) = (%q, %q), want non-empty public and private keys", c.algorithm, public, private)
+			}
+			if _, err := base64.StdEncoding.DecodeString(public); err != nil {
+				t.Errorf("public key isn't valid base64: %v", err)
+			}
+			if _, err := base64.StdEncoding.DecodeString(private); err != nil {
+				t.Errorf("private key isn't valid base64: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateECDSAP256KeyPair(t *testing.T) {
+	public, _, err := generateECDSAP256KeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(public)
+	if err != nil {
+		t.Fatalf("public key isn't valid base64: %v", err)
+	}
+	if len(raw) != 64 {
+		t.Errorf("public key is %d bytes, want 64 (RFC 6605 X||Y)", len(raw))
+	}
+}
+
+func TestGenerateRSASHA256KeyPair(t *testing.T) {
+	public, _, err := generateRSASHA256KeyPair()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(public)
+	if err != nil {
+		t.Fatalf("public key isn't valid base64: %v", err)
+	}
+	if len(raw) < 2 {
+		t.Fatalf("public key is %d bytes, too short to hold an exponent length and modulus", len(raw))
+	}
+	expLen := int(raw[0])
+	if len(raw) <= 1+expLen {
+		t.Errorf("public key is %d bytes, too short for a %d-byte exponent plus any modulus", len(raw), expLen)
+	}
+}
+
+func TestFixedWidthBytes(t *testing.T) {
+	cases := []struct {
+		name  string
+		in    int64
+		width int
+		want  []byte
+	}{
+		{name: "pads short values", in: 1, width: 4, want: []byte{0, 0, 0, 1}},
+		{name: "exact width", in: 0x01020304, width: 4, want: []byte{1, 2, 3, 4}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := fixedWidthBytes(big.NewInt(c.in), c.width)
+			if string(got) != string(c.want) {
+				t.Errorf("fixedWidthBytes(%d, %d) = %v, want %v", c.in, c.width, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,298 @@
+package cdn
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/trafficcontrol/lib/go-log"
+	"github.com/apache/trafficcontrol/lib/go-tc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/api"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/deliveryservice"
+)
+
+const (
+	dnskeyFlagsKSK     = 257
+	dnskeyFlagsZSK     = 256
+	dnskeyProtocol     = 3
+	dsDigestTypeSHA256 = 2
+)
+
+// DNSKEYRecord is a single DNSKEY resource record, as described by RFC 4034
+// Section 2.
+type DNSKEYRecord struct {
+	Name      string `json:"name"`
+	Flags     int    `json:"flags"`
+	Protocol  int    `json:"protocol"`
+	Algorithm uint8  `json:"algorithm"`
+	PublicKey string `json:"publicKey"`
+}
+
+// DSRecord is a single DS resource record, as described by RFC 4034 Section 5.
+type DSRecord struct {
+	Name       string `json:"name"`
+	KeyTag     uint16 `json:"keyTag"`
+	Algorithm  uint8  `json:"algorithm"`
+	DigestType int    `json:"digestType"`
+	Digest     string `json:"digest"`
+}
+
+// DNSSECZoneFile is the JSON-rendered form of GetDNSSECZoneFile's response;
+// the `text/dns` form renders the same data as a BIND zone fragment.
+type DNSSECZoneFile struct {
+	DNSKEY []DNSKEYRecord `json:"dnskey"`
+	DS     []DSRecord     `json:"ds"`
+}
+
+// GetDNSSECZoneFile renders the DNSKEY and DS records Traffic Ops expects
+// for a CDN's DNSSEC keys, so operators can diff them against what's
+// actually published at the parent zone.
+func GetDNSSECZoneFile(w http.ResponseWriter, r *http.Request) {
+	inf, userErr, sysErr, errCode := api.NewInfo(r, []string{"name"}, nil)
+	if userErr != nil || sysErr != nil {
+		api.HandleErr(w, r, errCode, userErr, sysErr)
+		return
+	}
+	defer inf.Close()
+
+	cdnName := inf.Params["name"]
+
+	keys, exists, err := inf.Store.GetDNSSECKeys(inf.Tx.Tx, cdnName)
+	if err != nil {
+		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("getting dnssec keys: "+err.Error()))
+		return
+	}
+	if !exists {
+		api.HandleErr(w, r, http.StatusNotFound, errors.New("no DNSSEC keys for CDN '"+cdnName+"'"), nil)
+		return
+	}
+
+	owners, err := dnssecOwnerNames(inf.Tx.Tx, cdnName)
+	if err != nil {
+		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("getting dnssec owner names: "+err.Error()))
+		return
+	}
+	*inf.CommitTx = true
+
+	zoneFile, err := buildDNSSECZoneFile(keys, owners)
+	if err != nil {
+		api.HandleErr(w, r, http.StatusInternalServerError, nil, errors.New("building zone file records: "+err.Error()))
+		return
+	}
+
+	if acceptsDNSZoneText(r) {
+		w.Header().Set("Content-Type", "text/dns")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(renderDNSSECZoneFileText(zoneFile)))
+		return
+	}
+	api.WriteResp(w, r, zoneFile)
+}
+
+// acceptsDNSZoneText returns whether the request prefers the raw zone text
+// form (Accept: text/dns) over the default JSON form.
+func acceptsDNSZoneText(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/dns")
+}
+
+// dnssecOwnerNames maps each key in a CDN's tc.DNSSECKeys (the CDN name
+// itself, and the xml_id of each of its delivery services) to the real DNS
+// owner name its records should be published under: the CDN's apex domain,
+// or the delivery service's routed FQDN.
+func dnssecOwnerNames(tx *sql.Tx, cdnName string) (map[string]string, error) {
+	dses, cdnDomain, err := getCDNDeliveryServices(tx, cdnName)
+	if err != nil {
+		return nil, errors.New("getting cdn delivery services: " + err.Error())
+	}
+
+	dsNames := make([]string, 0, len(dses))
+	for _, ds := range dses {
+		dsNames = append(dsNames, ds.Name)
+	}
+	matchLists, err := deliveryservice.GetDeliveryServicesMatchLists(dsNames, tx)
+	if err != nil {
+		return nil, errors.New("getting delivery service matchlists: " + err.Error())
+	}
+
+	owners := map[string]string{cdnName: cdnDomain}
+	for _, ds := range dses {
+		matchlist, ok := matchLists[ds.Name]
+		if !ok {
+			continue
+		}
+		exampleURLs := deliveryservice.MakeExampleURLs(ds.Protocol, ds.Type, ds.RoutingName, matchlist, cdnDomain)
+		if len(exampleURLs) == 0 {
+			continue
+		}
+		host, err := hostFromURL(exampleURLs[0])
+		if err != nil {
+			log.Warnf("dnssec zonefile: delivery service '%s' has an unparseable example URL '%s': %v", ds.Name, exampleURLs[0], err)
+			continue
+		}
+		owners[ds.Name] = host
+	}
+	return owners, nil
+}
+
+// hostFromURL returns the hostname portion of rawURL.
+func hostFromURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Hostname() == "" {
+		return "", errors.New("no host in URL '" + rawURL + "'")
+	}
+	return u.Hostname(), nil
+}
+
+// buildDNSSECZoneFile renders the DNSKEY and DS records for the CDN apex
+// and every delivery service in keys. owners maps each key's map key (the
+// CDN name or a delivery service's xml_id) to the real DNS owner name its
+// records should be published under, as built by dnssecOwnerNames; if a
+// name has no entry, the raw map key is used instead.
+func buildDNSSECZoneFile(keys tc.DNSSECKeys, owners map[string]string) (DNSSECZoneFile, error) {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names) // deterministic output
+
+	zoneFile := DNSSECZoneFile{}
+	for _, name := range names {
+		owner, ok := owners[name]
+		if !ok {
+			log.Warnf("dnssec zonefile: no real DNS owner name found for '%s', falling back to the raw key name", name)
+			owner = name
+		}
+		keySet := keys[name]
+		for _, ksk := range keySet.KSK {
+			dnskey, ds, err := dnssecRecordsForKey(owner, dnskeyFlagsKSK, ksk)
+			if err != nil {
+				return DNSSECZoneFile{}, errors.New("building records for '" + name + "' KSK: " + err.Error())
+			}
+			zoneFile.DNSKEY = append(zoneFile.DNSKEY, dnskey)
+			zoneFile.DS = append(zoneFile.DS, ds)
+		}
+		for _, zsk := range keySet.ZSK {
+			dnskey, ds, err := dnssecRecordsForKey(owner, dnskeyFlagsZSK, zsk)
+			if err != nil {
+				return DNSSECZoneFile{}, errors.New("building records for '" + name + "' ZSK: " + err.Error())
+			}
+			zoneFile.DNSKEY = append(zoneFile.DNSKEY, dnskey)
+			zoneFile.DS = append(zoneFile.DS, ds)
+		}
+	}
+	return zoneFile, nil
+}
+
+// dnssecRecordsForKey builds the DNSKEY and DS records for a single stored
+// key owned by owner, its real DNS owner name.
+func dnssecRecordsForKey(owner string, flags int, key tc.DNSSECKey) (DNSKEYRecord, DSRecord, error) {
+	algorithm := dnssecKeyAlgorithm(key)
+	publicKey, err := base64.StdEncoding.DecodeString(key.Public)
+	if err != nil {
+		return DNSKEYRecord{}, DSRecord{}, errors.New("decoding public key: " + err.Error())
+	}
+
+	rdata := dnskeyRDATA(flags, algorithm, publicKey)
+	keyTag := calcDNSKEYKeyTag(rdata)
+	digest := sha256.Sum256(append(wireName(owner), rdata...))
+
+	dnskey := DNSKEYRecord{
+		Name:      owner,
+		Flags:     flags,
+		Protocol:  dnskeyProtocol,
+		Algorithm: algorithm,
+		PublicKey: key.Public,
+	}
+	ds := DSRecord{
+		Name:       owner,
+		KeyTag:     keyTag,
+		Algorithm:  algorithm,
+		DigestType: dsDigestTypeSHA256,
+		Digest:     hex.EncodeToString(digest[:]),
+	}
+	return dnskey, ds, nil
+}
+
+// dnskeyRDATA builds the RDATA of a DNSKEY resource record per RFC 4034
+// Section 2.2: 2-octet flags, 1-octet protocol (always 3), 1-octet
+// algorithm, followed by the raw public key.
+func dnskeyRDATA(flags int, algorithm uint8, publicKey []byte) []byte {
+	rdata := make([]byte, 0, 4+len(publicKey))
+	rdata = append(rdata, byte(flags>>8), byte(flags))
+	rdata = append(rdata, dnskeyProtocol, algorithm)
+	rdata = append(rdata, publicKey...)
+	return rdata
+}
+
+// calcDNSKEYKeyTag computes a DNSKEY's key tag from its RDATA, per the
+// reference implementation in RFC 4034 Appendix B.
+func calcDNSKEYKeyTag(rdata []byte) uint16 {
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 1 {
+			ac += uint32(b)
+		} else {
+			ac += uint32(b) << 8
+		}
+	}
+	ac += (ac >> 16) & 0xFFFF
+	return uint16(ac & 0xFFFF)
+}
+
+// wireName encodes a domain name in DNS wire format (length-prefixed
+// labels terminated by a zero-length root label), as used when hashing a DS
+// record's owner name.
+func wireName(name string) []byte {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	buf := bytes.Buffer{}
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			buf.WriteByte(byte(len(label)))
+			buf.WriteString(label)
+		}
+	}
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+// renderDNSSECZoneFileText renders zoneFile as a BIND-style zone fragment.
+func renderDNSSECZoneFileText(zoneFile DNSSECZoneFile) string {
+	lines := make([]string, 0, len(zoneFile.DNSKEY)+len(zoneFile.DS))
+	for _, k := range zoneFile.DNSKEY {
+		lines = append(lines, k.Name+".\tIN\tDNSKEY\t"+strconv.Itoa(k.Flags)+" "+strconv.Itoa(k.Protocol)+" "+strconv.Itoa(int(k.Algorithm))+" "+k.PublicKey)
+	}
+	for _, d := range zoneFile.DS {
+		lines = append(lines, d.Name+".\tIN\tDS\t"+strconv.Itoa(int(d.KeyTag))+" "+strconv.Itoa(int(d.Algorithm))+" "+strconv.Itoa(d.DigestType)+" "+d.Digest)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
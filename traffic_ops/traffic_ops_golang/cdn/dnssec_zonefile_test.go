@@ -0,0 +1,110 @@
+package cdn
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+)
+
+func TestWireName(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want []byte
+	}{
+		{name: "simple", in: "example.com", want: []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}},
+		{name: "trailing dot stripped", in: "example.com.", want: []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}},
+		{name: "lowercased", in: "EXAMPLE.com", want: []byte{7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0}},
+		{name: "root", in: "", want: []byte{0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := wireName(c.in)
+			if string(got) != string(c.want) {
+				t.Errorf("wireName(%q) = %v, want %v", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCalcDNSKEYKeyTag(t *testing.T) {
+	// A zero-length RDATA should produce a zero key tag: there's no
+	// octet data to accumulate, so both the even- and odd-indexed
+	// running sums are zero before the final fold.
+	if tag := calcDNSKEYKeyTag(nil); tag != 0 {
+		t.Errorf("calcDNSKEYKeyTag(nil) = %d, want 0", tag)
+	}
+
+	rdata := dnskeyRDATA(dnskeyFlagsZSK, DNSSECAlgorithmRSASHA256, []byte{0x01, 0x02, 0x03})
+	if tag := calcDNSKEYKeyTag(rdata); tag == 0 {
+		t.Error("calcDNSKEYKeyTag(rdata) = 0, want a non-zero tag for non-empty RDATA")
+	}
+}
+
+func TestHostFromURL(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{name: "https with path", in: "https://foo.example.com/bar", want: "foo.example.com"},
+		{name: "http with port", in: "http://foo.example.com:80/", want: "foo.example.com"},
+		{name: "no host", in: "/just/a/path", wantErr: true},
+		{name: "unparseable", in: "://bad", wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := hostFromURL(c.in)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("hostFromURL(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDnssecRecordsForKeyUsesOwnerName(t *testing.T) {
+	key := tc.DNSSECKey{
+		Algorithm: DNSSECAlgorithmRSASHA256,
+		Public:    base64.StdEncoding.EncodeToString([]byte{0x01, 0x02, 0x03}),
+	}
+	dnskey, ds, err := dnssecRecordsForKey("ds1.example.com", dnskeyFlagsZSK, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dnskey.Name != "ds1.example.com" {
+		t.Errorf("dnskey.Name = %q, want %q", dnskey.Name, "ds1.example.com")
+	}
+	if ds.Name != "ds1.example.com" {
+		t.Errorf("ds.Name = %q, want %q", ds.Name, "ds1.example.com")
+	}
+}
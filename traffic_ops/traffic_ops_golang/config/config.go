@@ -0,0 +1,51 @@
+package config
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/riaksvc"
+)
+
+// Config holds the Traffic Ops API's runtime configuration, as loaded from
+// cdn.conf.
+type Config struct {
+	// RiakAuthOptions carries the credentials used to reach the Riak
+	// cluster, for any component still using the default "riak" secrets
+	// backend.
+	RiakAuthOptions *riaksvc.AuthOptions
+
+	// SecretsBackend selects which backend keystore.NewFromConfig builds:
+	// "riak" (the default, for backward compatibility), "vault", or
+	// "memory". Empty behaves as "riak".
+	SecretsBackend string
+
+	// VaultConfig carries the Vault connection details used when
+	// SecretsBackend is "vault". It's nil otherwise.
+	VaultConfig *VaultConfig
+}
+
+// VaultConfig carries the connection details for a Vault-backed secrets
+// store: where to reach Vault, what token to authenticate with, and which
+// KV v2 mount to read and write secrets under.
+type VaultConfig struct {
+	Address string `json:"address"`
+	Token   string `json:"token"`
+	Mount   string `json:"mount"`
+}
@@ -22,7 +22,10 @@ package dbhelpers
 import (
 	"database/sql"
 	"errors"
+	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/apache/trafficcontrol/lib/go-log"
 	"github.com/apache/trafficcontrol/lib/go-tc"
@@ -31,13 +34,42 @@ import (
 	"github.com/lib/pq"
 )
 
+// WhereColumnInfo describes how a single query parameter maps to a SQL
+// column for BuildWhereAndOrderBy.
 type WhereColumnInfo struct {
 	Column  string
 	Checker func(string) error
+	// Op is the SQL comparison operator used to build this column's WHERE
+	// clause fragment: one of =, <, >, <=, >=, LIKE, or IN. Defaults to =.
+	// It's overridden per-request by a suffixed query param, e.g.
+	// `?createdAt.gte=...`.
+	Op string
 }
 
 const BaseWhere = "\nWHERE"
 const BaseOrderBy = "\nORDER BY"
+const BaseLimit = "\nLIMIT"
+const BaseOffset = "\nOFFSET"
+
+// SQL comparison operators recognized in WhereColumnInfo.Op.
+const (
+	OpEqual              = "="
+	OpLessThan           = "<"
+	OpGreaterThan        = ">"
+	OpLessThanOrEqual    = "<="
+	OpGreaterThanOrEqual = ">="
+	OpLike               = "LIKE"
+	OpIn                 = "IN"
+)
+
+// querySuffixOps maps the suffix of a query param like `createdAt.gte` to
+// the SQL operator it requests, overriding the column's default Op.
+var querySuffixOps = map[string]string{
+	"gte": OpGreaterThanOrEqual,
+	"lte": OpLessThanOrEqual,
+	"gt":  OpGreaterThan,
+	"lt":  OpLessThan,
+}
 
 func BuildWhereAndOrderBy(parameters map[string]string, queryParamsToSQLCols map[string]WhereColumnInfo) (string, string, map[string]interface{}, []error) {
 	whereClause := BaseWhere
@@ -59,6 +91,16 @@ func BuildWhereAndOrderBy(parameters map[string]string, queryParamsToSQLCols map
 		if colInfo, ok := queryParamsToSQLCols[orderby]; ok {
 			log.Debugln("orderby column ", colInfo)
 			orderBy += " " + colInfo.Column
+			if sortOrder, ok := parameters["sortOrder"]; ok {
+				switch strings.ToLower(sortOrder) {
+				case "desc":
+					orderBy += " DESC"
+				case "asc":
+					orderBy += " ASC"
+				default:
+					log.Debugln("Incorrect order for sortOrder: ", sortOrder)
+				}
+			}
 		} else {
 			log.Debugln("Incorrect name for orderby: ", orderby)
 		}
@@ -73,51 +115,165 @@ func BuildWhereAndOrderBy(parameters map[string]string, queryParamsToSQLCols map
 	return whereClause, orderBy, queryValues, errs
 }
 
-func parseCriteriaAndQueryValues(queryParamsToSQLCols map[string]WhereColumnInfo, parameters map[string]string) (string, map[string]interface{}, []error) {
-	m := make(map[string]interface{})
-	var criteria string
+// BuildLimitOffset returns a "LIMIT :limit [OFFSET :offset]" SQL fragment
+// and its bound params, parsed from the `limit` and `offset` query
+// parameters. It returns an empty fragment if limit isn't given, since an
+// OFFSET without a LIMIT isn't useful pagination.
+func BuildLimitOffset(parameters map[string]string) (string, map[string]interface{}, error) {
+	queryValues := make(map[string]interface{})
+	limitParam, ok := parameters["limit"]
+	if !ok {
+		return "", queryValues, nil
+	}
+	limit, err := strconv.Atoi(limitParam)
+	if err != nil || limit < 0 {
+		return "", queryValues, errors.New("limit must be a non-negative integer")
+	}
+	fragment := BaseLimit + " :limit"
+	queryValues["limit"] = limit
 
-	var criteriaArgs []string
+	if offsetParam, ok := parameters["offset"]; ok {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return "", queryValues, errors.New("offset must be a non-negative integer")
+		}
+		fragment += BaseOffset + " :offset"
+		queryValues["offset"] = offset
+	}
+	return fragment, queryValues, nil
+}
+
+// parseCriteriaAndQueryValues builds the `AND`-joined WHERE criteria for
+// every query parameter in parameters that matches a column in
+// queryParamsToSQLCols, either directly (`?xmlId=foo`) or via a suffixed
+// comparison operator (`?createdAt.gte=2019-01-01`). A column configured
+// with Op: OpIn, or any comma-separated value (`?xmlId=foo,bar`) against
+// the default `=` operator, is matched with `= ANY(...)` against each
+// value rather than the column's configured operator.
+func parseCriteriaAndQueryValues(queryParamsToSQLCols map[string]WhereColumnInfo, parameters map[string]string) (string, map[string]interface{}, []error) {
+	criteriaArgs := []string{}
 	errs := []error{}
 	queryValues := make(map[string]interface{})
-	for key, colInfo := range queryParamsToSQLCols {
-		if urlValue, ok := parameters[key]; ok {
-			var err error
-			if colInfo.Checker != nil {
-				err = colInfo.Checker(urlValue)
+
+	for paramKey, urlValue := range parameters {
+		key := paramKey
+		op := ""
+		if idx := strings.LastIndex(paramKey, "."); idx != -1 {
+			if suffixOp, ok := querySuffixOps[paramKey[idx+1:]]; ok {
+				key = paramKey[:idx]
+				op = suffixOp
 			}
-			if err != nil {
+		}
+
+		colInfo, ok := queryParamsToSQLCols[key]
+		if !ok {
+			continue
+		}
+		if colInfo.Checker != nil {
+			if err := colInfo.Checker(urlValue); err != nil {
 				errs = append(errs, errors.New(key+" "+err.Error()))
-			} else {
-				m[key] = urlValue
-				criteria = colInfo.Column + "=:" + key
-				criteriaArgs = append(criteriaArgs, criteria)
-				queryValues[key] = urlValue
+				continue
 			}
 		}
+		if op == "" {
+			op = colInfo.Op
+		}
+		if op == "" {
+			op = OpEqual
+		}
+
+		bindKey := strings.Replace(paramKey, ".", "_", -1)
+		if op == OpIn || (op == OpEqual && strings.Contains(urlValue, ",")) {
+			queryValues[bindKey] = pq.Array(strings.Split(urlValue, ","))
+			criteriaArgs = append(criteriaArgs, colInfo.Column+" = ANY(:"+bindKey+")")
+			continue
+		}
+
+		queryValues[bindKey] = urlValue
+		criteriaArgs = append(criteriaArgs, colInfo.Column+" "+op+" :"+bindKey)
 	}
-	criteria = strings.Join(criteriaArgs, " AND ")
 
+	criteria := strings.Join(criteriaArgs, " AND ")
 	return criteria, queryValues, errs
 }
 
-//parses pq errors for uniqueness constraint violations
-func ParsePQUniqueConstraintError(err *pq.Error) (error, tc.ApiErrorType) {
-	if len(err.Constraint) > 0 && len(err.Detail) > 0 { //we only want to continue parsing if it is a constraint error with details
-		detail := err.Detail
-		if strings.HasPrefix(detail, "Key ") && strings.HasSuffix(detail, " already exists.") { //we only want to continue parsing if it is a uniqueness constraint error
-			detail = strings.TrimPrefix(detail, "Key ")
-			detail = strings.TrimSuffix(detail, " already exists.")
-			//should look like "(column)=(dupe value)" at this point
-			details := strings.Split(detail, "=")
-			if len(details) == 2 {
-				column := strings.Trim(details[0], "()")
-				dupValue := strings.Trim(details[1], "()")
-				return errors.New(column + " " + dupValue + " already exists."), tc.DataConflictError
-			}
+// PostgreSQL SQLSTATE codes this package knows how to classify. See
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqCodeUniqueViolation      = "23505"
+	pqCodeForeignKeyViolation  = "23503"
+	pqCodeNotNullViolation     = "23502"
+	pqCodeCheckViolation       = "23514"
+	pqCodeInvalidTextRep       = "22P02"
+	pqCodeStringDataRightTrunc = "22001"
+	pqCodeSerializationFailure = "40001"
+	pqCodeDeadlockDetected     = "40P01"
+)
+
+var constraintMessagesMu sync.RWMutex
+var constraintMessages = map[string]string{}
+
+// RegisterConstraint associates a PostgreSQL constraint name with the
+// human-readable message ClassifyPQError should report when it's violated,
+// e.g. RegisterConstraint("profile_name_key", "a profile with this name").
+// Packages should call it from their init() so they don't need to
+// duplicate constraint-name knowledge at every call site.
+func RegisterConstraint(name string, humanMessage string) {
+	constraintMessagesMu.Lock()
+	defer constraintMessagesMu.Unlock()
+	constraintMessages[name] = humanMessage
+}
+
+// constraintMessage returns the registered human-readable message for a
+// constraint name, or the constraint name itself if nothing's registered.
+func constraintMessage(name string) string {
+	constraintMessagesMu.RLock()
+	defer constraintMessagesMu.RUnlock()
+	if msg, ok := constraintMessages[name]; ok {
+		return msg
+	}
+	return name
+}
+
+// ClassifyPQError maps a PostgreSQL error to a user-facing error, a
+// system (log-only) error, and the HTTP status code a handler should
+// respond with. It switches on err.Code (the SQLSTATE), which is stable
+// across locales and PostgreSQL versions, rather than string-matching
+// err.Detail.
+func ClassifyPQError(err *pq.Error) (userErr error, sysErr error, statusCode int) {
+	switch string(err.Code) {
+	case pqCodeUniqueViolation:
+		return errors.New(constraintMessage(err.Constraint) + " already exists"), nil, http.StatusConflict
+	case pqCodeForeignKeyViolation:
+		// 23503 fires in both directions: deleting a row some other table
+		// still references, or inserting/updating a row that references a
+		// parent which doesn't exist. Postgres' Detail text is the only way
+		// to tell them apart.
+		if strings.Contains(err.Detail, "is still referenced from table") {
+			return errors.New("cannot remove, referenced by " + constraintMessage(err.Constraint)), nil, http.StatusConflict
 		}
+		return errors.New(constraintMessage(err.Constraint) + ": " + err.Detail), nil, http.StatusBadRequest
+	case pqCodeNotNullViolation:
+		return errors.New(err.Column + " is required"), nil, http.StatusBadRequest
+	case pqCodeCheckViolation:
+		return errors.New(constraintMessage(err.Constraint)), nil, http.StatusBadRequest
+	case pqCodeInvalidTextRep, pqCodeStringDataRightTrunc:
+		return errors.New("invalid value: " + err.Message), nil, http.StatusBadRequest
+	case pqCodeSerializationFailure, pqCodeDeadlockDetected:
+		return errors.New("a conflicting request was in progress, please retry"), nil, http.StatusServiceUnavailable
+	default:
+		return tc.DBError, errors.New("classifying pq error: " + err.Error()), http.StatusInternalServerError
+	}
+}
+
+// ParsePQUniqueConstraintError is deprecated; use ClassifyPQError instead,
+// which classifies by SQLSTATE rather than string-matching err.Detail and
+// covers more error classes than just uniqueness violations.
+func ParsePQUniqueConstraintError(err *pq.Error) (error, tc.ApiErrorType) {
+	userErr, _, statusCode := ClassifyPQError(err)
+	if statusCode == http.StatusConflict {
+		return userErr, tc.DataConflictError
 	}
-	log.Error.Printf("failed to parse unique constraint from pq error: %v", err)
 	return tc.DBError, tc.SystemError
 }
 
@@ -0,0 +1,209 @@
+package dbhelpers
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+
+	"github.com/lib/pq"
+)
+
+func TestParseCriteriaAndQueryValues(t *testing.T) {
+	cols := map[string]WhereColumnInfo{
+		"xmlId":     {Column: "ds.xml_id"},
+		"createdAt": {Column: "ds.created_at"},
+		"type":      {Column: "ds.type", Op: OpIn},
+	}
+
+	cases := []struct {
+		name     string
+		params   map[string]string
+		wantCrit string
+		wantIsIn bool
+		wantBind string
+	}{
+		{
+			name:     "default equal",
+			params:   map[string]string{"xmlId": "foo"},
+			wantCrit: "ds.xml_id = :xmlId",
+			wantBind: "xmlId",
+		},
+		{
+			name:     "suffix operator",
+			params:   map[string]string{"createdAt.gte": "2019-01-01"},
+			wantCrit: "ds.created_at >= :createdAt_gte",
+			wantBind: "createdAt_gte",
+		},
+		{
+			name:     "comma value against default equal becomes ANY",
+			params:   map[string]string{"xmlId": "foo,bar"},
+			wantCrit: "ds.xml_id = ANY(:xmlId)",
+			wantIsIn: true,
+			wantBind: "xmlId",
+		},
+		{
+			name:     "explicit OpIn with single value still uses ANY",
+			params:   map[string]string{"type": "HTTP"},
+			wantCrit: "ds.type = ANY(:type)",
+			wantIsIn: true,
+			wantBind: "type",
+		},
+		{
+			name:     "explicit OpIn with comma value uses ANY",
+			params:   map[string]string{"type": "HTTP,DNS"},
+			wantCrit: "ds.type = ANY(:type)",
+			wantIsIn: true,
+			wantBind: "type",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			criteria, queryValues, errs := parseCriteriaAndQueryValues(cols, c.params)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if criteria != c.wantCrit {
+				t.Errorf("criteria = %q, want %q", criteria, c.wantCrit)
+			}
+			if c.wantIsIn {
+				if _, ok := queryValues[c.wantBind].(*pq.StringArray); !ok {
+					t.Errorf("queryValues[%q] = %#v, want a *pq.StringArray", c.wantBind, queryValues[c.wantBind])
+				}
+			}
+		})
+	}
+}
+
+func TestBuildLimitOffset(t *testing.T) {
+	cases := []struct {
+		name       string
+		params     map[string]string
+		wantFrag   string
+		wantErr    bool
+		wantLimit  int
+		wantOffset int
+		hasOffset  bool
+	}{
+		{name: "no limit", params: map[string]string{}, wantFrag: ""},
+		{name: "limit only", params: map[string]string{"limit": "10"}, wantFrag: BaseLimit + " :limit", wantLimit: 10},
+		{
+			name:       "limit and offset",
+			params:     map[string]string{"limit": "10", "offset": "5"},
+			wantFrag:   BaseLimit + " :limit" + BaseOffset + " :offset",
+			wantLimit:  10,
+			wantOffset: 5,
+			hasOffset:  true,
+		},
+		{name: "negative limit errors", params: map[string]string{"limit": "-1"}, wantErr: true},
+		{name: "non-numeric offset errors", params: map[string]string{"limit": "10", "offset": "abc"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			frag, queryValues, err := BuildLimitOffset(c.params)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if frag != c.wantFrag {
+				t.Errorf("fragment = %q, want %q", frag, c.wantFrag)
+			}
+			if c.wantFrag == "" {
+				return
+			}
+			if queryValues["limit"] != c.wantLimit {
+				t.Errorf("limit = %v, want %v", queryValues["limit"], c.wantLimit)
+			}
+			if c.hasOffset && queryValues["offset"] != c.wantOffset {
+				t.Errorf("offset = %v, want %v", queryValues["offset"], c.wantOffset)
+			}
+		})
+	}
+}
+
+func TestClassifyPQError(t *testing.T) {
+	cases := []struct {
+		name       string
+		err        *pq.Error
+		wantStatus int
+	}{
+		{
+			name:       "unique violation",
+			err:        &pq.Error{Code: pqCodeUniqueViolation, Constraint: "profile_name_key"},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "fk violation on delete",
+			err:        &pq.Error{Code: pqCodeForeignKeyViolation, Constraint: "fk_cdn", Detail: "Key (id)=(1) is still referenced from table \"deliveryservice\"."},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "fk violation on insert",
+			err:        &pq.Error{Code: pqCodeForeignKeyViolation, Constraint: "fk_cdn", Detail: "Key (cdn_id)=(99) is not present in table \"cdn\"."},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not null violation",
+			err:        &pq.Error{Code: pqCodeNotNullViolation, Column: "xml_id"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "serialization failure",
+			err:        &pq.Error{Code: pqCodeSerializationFailure},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:       "unrecognized code",
+			err:        &pq.Error{Code: "99999"},
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			userErr, _, statusCode := ClassifyPQError(c.err)
+			if statusCode != c.wantStatus {
+				t.Errorf("statusCode = %d, want %d", statusCode, c.wantStatus)
+			}
+			if userErr == nil {
+				t.Error("expected a non-nil user error")
+			}
+		})
+	}
+}
+
+func TestParsePQUniqueConstraintError(t *testing.T) {
+	userErr, apiErrType := ParsePQUniqueConstraintError(&pq.Error{Code: pqCodeUniqueViolation, Constraint: "profile_name_key"})
+	if userErr == nil {
+		t.Error("expected a non-nil user error for a unique violation")
+	}
+	if apiErrType != tc.DataConflictError {
+		t.Errorf("apiErrType = %v, want tc.DataConflictError", apiErrType)
+	}
+}
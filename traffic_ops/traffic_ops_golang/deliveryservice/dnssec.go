@@ -0,0 +1,65 @@
+package deliveryservice
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+)
+
+// CreateDNSSECKeys builds a delivery service's DNSSEC key set: a freshly
+// generated ZSK of its own, signed by the parent CDN's KSK (cdnKeys.KSK),
+// since delivery services don't hold a separate key signing chain. tx and
+// exampleURLs aren't used to generate key material; they're accepted so
+// callers that need to validate or log against them (e.g. confirming the
+// delivery service actually resolves under exampleURLs) can do so with the
+// same call. kExp is accepted for symmetry with zExp, since a future KSK
+// rotation per delivery service would need it, but isn't used today.
+func CreateDNSSECKeys(tx *sql.Tx, dsName string, exampleURLs []string, cdnKeys tc.DNSSECKeySet, kExp time.Duration, zExp time.Duration, ttl time.Duration, overrideTTL bool, algorithm uint8) (tc.DNSSECKeySet, error) {
+	public, private, err := generateDNSSECKeyPair(algorithm)
+	if err != nil {
+		return tc.DNSSECKeySet{}, errors.New("generating delivery service zsk: " + err.Error())
+	}
+
+	now := time.Now().Unix()
+	zsk := tc.DNSSECKey{
+		Name:               dsName,
+		Status:             "new",
+		TTLSeconds:         uint64(ttl / time.Second),
+		Algorithm:          algorithm,
+		Public:             public,
+		Private:            private,
+		InceptionDateUnix:  now,
+		ExpirationDateUnix: now + int64(zExp/time.Second),
+	}
+
+	ksk := make([]tc.DNSSECKey, len(cdnKeys.KSK))
+	copy(ksk, cdnKeys.KSK)
+	if overrideTTL {
+		for i := range ksk {
+			ksk[i].TTLSeconds = uint64(ttl / time.Second)
+		}
+	}
+
+	return tc.DNSSECKeySet{ZSK: []tc.DNSSECKey{zsk}, KSK: ksk}, nil
+}
@@ -0,0 +1,124 @@
+package deliveryservice
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strconv"
+)
+
+// DNSSEC algorithm numbers this package knows how to generate keys for.
+// These mirror the cdn package's exported DNSSECAlgorithm* constants; the two
+// packages can't share code directly, since cdn already imports
+// deliveryservice.
+const (
+	dnssecAlgorithmRSASHA256       = uint8(8)
+	dnssecAlgorithmECDSAP256SHA256 = uint8(13)
+)
+
+// rsaKeyBits is the modulus size used for newly-generated RSASHA256 keys.
+const rsaKeyBits = 2048
+
+// generateDNSSECKeyPair generates a new key pair for algorithm and returns
+// its public and private halves, both base64-encoded: the public key ready
+// to drop into a DNSKEY record's RDATA, and the private key as PKCS#8 DER.
+func generateDNSSECKeyPair(algorithm uint8) (public string, private string, err error) {
+	switch algorithm {
+	case dnssecAlgorithmECDSAP256SHA256:
+		return generateECDSAP256KeyPair()
+	case dnssecAlgorithmRSASHA256:
+		return generateRSASHA256KeyPair()
+	default:
+		return "", "", errors.New("unsupported DNSSEC algorithm " + strconv.Itoa(int(algorithm)))
+	}
+}
+
+// generateECDSAP256KeyPair generates an ECDSA P-256 key pair. The public
+// key is encoded per RFC 6605 Section 4: the 64-byte concatenation of the
+// curve point's X and Y coordinates, each left-padded to 32 bytes.
+func generateECDSAP256KeyPair() (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", errors.New("generating ecdsa key: " + err.Error())
+	}
+
+	pubKey := make([]byte, 0, 64)
+	pubKey = append(pubKey, fixedWidthBytes(key.X, 32)...)
+	pubKey = append(pubKey, fixedWidthBytes(key.Y, 32)...)
+
+	privKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", errors.New("marshaling ecdsa private key: " + err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(pubKey), base64.StdEncoding.EncodeToString(privKey), nil
+}
+
+// generateRSASHA256KeyPair generates an RSA key pair. The public key is
+// encoded per RFC 3110: a length-prefixed exponent followed by the modulus.
+func generateRSASHA256KeyPair() (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	if err != nil {
+		return "", "", errors.New("generating rsa key: " + err.Error())
+	}
+
+	privKey, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", errors.New("marshaling rsa private key: " + err.Error())
+	}
+	return base64.StdEncoding.EncodeToString(rsaDNSKEYPublicKey(&key.PublicKey)), base64.StdEncoding.EncodeToString(privKey), nil
+}
+
+// rsaDNSKEYPublicKey encodes an RSA public key as DNSKEY RDATA public key
+// material, per RFC 3110 Section 2: a one-octet exponent length (or, if the
+// exponent is too long to fit in one octet, a zero octet followed by a
+// two-octet length), the exponent, then the modulus.
+func rsaDNSKEYPublicKey(pub *rsa.PublicKey) []byte {
+	exp := big.NewInt(int64(pub.E)).Bytes()
+	mod := pub.N.Bytes()
+
+	buf := make([]byte, 0, 4+len(exp)+len(mod))
+	if len(exp) <= 255 {
+		buf = append(buf, byte(len(exp)))
+	} else {
+		buf = append(buf, 0, byte(len(exp)>>8), byte(len(exp)))
+	}
+	buf = append(buf, exp...)
+	buf = append(buf, mod...)
+	return buf
+}
+
+// fixedWidthBytes returns n's big-endian bytes, left-padded with zeroes to
+// width bytes.
+func fixedWidthBytes(n *big.Int, width int) []byte {
+	b := n.Bytes()
+	if len(b) >= width {
+		return b[len(b)-width:]
+	}
+	padded := make([]byte, width)
+	copy(padded[width-len(b):], b)
+	return padded
+}
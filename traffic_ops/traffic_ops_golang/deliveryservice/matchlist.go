@@ -0,0 +1,146 @@
+package deliveryservice
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+
+	"github.com/lib/pq"
+)
+
+// MatchList is a delivery service's ordered list of host-header regexes,
+// used to build the example URLs its DNSSEC keys are issued for.
+type MatchList []string
+
+// Delivery service protocol values, as stored in deliveryservice.protocol.
+const (
+	ProtocolHTTP         = 0
+	ProtocolHTTPS        = 1
+	ProtocolHTTPAndHTTPS = 2
+	ProtocolHTTPToHTTPS  = 3
+)
+
+// GetDeliveryServicesMatchLists returns the host-header regex match list
+// for each named delivery service, keyed by xml_id.
+func GetDeliveryServicesMatchLists(dsNames []string, tx *sql.Tx) (map[string]MatchList, error) {
+	matchLists := make(map[string]MatchList, len(dsNames))
+	for _, dsName := range dsNames {
+		matchLists[dsName] = MatchList{}
+	}
+	if len(dsNames) == 0 {
+		return matchLists, nil
+	}
+
+	q := `
+SELECT ds.xml_id, r.pattern
+FROM deliveryservice_regex AS dsr
+JOIN regex AS r ON dsr.regex = r.id
+JOIN deliveryservice AS ds ON dsr.deliveryservice = ds.id
+WHERE ds.xml_id = ANY($1)
+ORDER BY ds.xml_id, dsr.set_number
+`
+	rows, err := tx.Query(q, pq.Array(dsNames))
+	if err != nil {
+		return nil, errors.New("querying delivery service match lists: " + err.Error())
+	}
+	defer rows.Close()
+	for rows.Next() {
+		dsName := ""
+		pattern := ""
+		if err := rows.Scan(&dsName, &pattern); err != nil {
+			return nil, errors.New("scanning delivery service match list: " + err.Error())
+		}
+		matchLists[dsName] = append(matchLists[dsName], pattern)
+	}
+	return matchLists, nil
+}
+
+// MakeExampleURLs builds the example URLs a delivery service's DNSSEC keys
+// are issued for: one per match list pattern, routed under the delivery
+// service's routing name and the CDN's domain, over whichever of HTTP/HTTPS
+// the delivery service's protocol allows.
+func MakeExampleURLs(protocol *int, dsType tc.DSType, routingName string, matchlist MatchList, cdnDomain string) []string {
+	schemes := exampleURLSchemes(protocol)
+	urls := make([]string, 0, len(matchlist)*len(schemes))
+	for _, pattern := range matchlist {
+		host := exampleHostFromPattern(pattern, dsType, routingName, cdnDomain)
+		if host == "" {
+			continue
+		}
+		for _, scheme := range schemes {
+			urls = append(urls, scheme+"://"+host)
+		}
+	}
+	return urls
+}
+
+// exampleURLSchemes returns which of "http"/"https" a delivery service's
+// protocol serves traffic over.
+func exampleURLSchemes(protocol *int) []string {
+	if protocol == nil {
+		return []string{"http"}
+	}
+	switch *protocol {
+	case ProtocolHTTPS:
+		return []string{"https"}
+	case ProtocolHTTPAndHTTPS, ProtocolHTTPToHTTPS:
+		return []string{"http", "https"}
+	default:
+		return []string{"http"}
+	}
+}
+
+// exampleHostFromPattern turns a match list's regex pattern into a concrete
+// example hostname: DNS-routed delivery services are reachable directly at
+// their routing name under the CDN domain; HTTP-routed ones are reachable
+// at their routing name, then the host part their regex matches (with the
+// regex metacharacters and any leading or trailing dots stripped), under
+// the CDN domain.
+func exampleHostFromPattern(pattern string, dsType tc.DSType, routingName string, cdnDomain string) string {
+	if dsType.IsDNS() {
+		return routingName + "." + cdnDomain
+	}
+	host := pattern
+	for _, c := range []string{`\`, `.*`, `^`, `$`} {
+		host = stripAll(host, c)
+	}
+	host = strings.Trim(host, ".")
+	if host == "" {
+		return ""
+	}
+	return routingName + "." + host + "." + cdnDomain
+}
+
+func stripAll(s string, substr string) string {
+	result := make([]byte, 0, len(s))
+	for i := 0; i < len(s); {
+		if i+len(substr) <= len(s) && s[i:i+len(substr)] == substr {
+			i += len(substr)
+			continue
+		}
+		result = append(result, s[i])
+		i++
+	}
+	return string(result)
+}
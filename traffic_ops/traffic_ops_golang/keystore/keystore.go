@@ -0,0 +1,69 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/config"
+)
+
+// Store abstracts the secret backend that holds DNSSEC, URL signature, and
+// SSL keys. Callers (cdn, deliveryservice handlers) shouldn't need to know
+// whether keys live in Riak, Vault, or nowhere at all (tests); they just
+// need a Store.
+type Store interface {
+	GetDNSSECKeys(tx *sql.Tx, cdnName string) (tc.DNSSECKeys, bool, error)
+	PutDNSSECKeys(tx *sql.Tx, cdnName string, keys tc.DNSSECKeys) error
+	DeleteDNSSECKeys(tx *sql.Tx, cdnName string) error
+
+	GetURLSigKeys(tx *sql.Tx, dsName string) (tc.URLSigKeys, bool, error)
+	PutURLSigKeys(tx *sql.Tx, dsName string, keys tc.URLSigKeys) error
+	DeleteURLSigKeys(tx *sql.Tx, dsName string) error
+
+	GetSSLKeys(tx *sql.Tx, dsName string, version string) (tc.SSLKeys, bool, error)
+	PutSSLKeys(tx *sql.Tx, dsName string, version string, keys tc.SSLKeys) error
+	DeleteSSLKeys(tx *sql.Tx, dsName string, version string) error
+}
+
+// Backend names accepted by the `secrets_backend` config field.
+const (
+	BackendRiak   = "riak"
+	BackendVault  = "vault"
+	BackendMemory = "memory"
+)
+
+// NewFromConfig builds the Store selected by cfg.SecretsBackend, defaulting
+// to the Riak-backed Store if the field is unset, since that's every
+// existing install's behavior before secrets_backend was introduced.
+func NewFromConfig(cfg *config.Config) (Store, error) {
+	switch cfg.SecretsBackend {
+	case "", BackendRiak:
+		return NewRiakStore(cfg.RiakAuthOptions), nil
+	case BackendVault:
+		return NewVaultStore(cfg.VaultConfig)
+	case BackendMemory:
+		return NewMemoryStore(), nil
+	default:
+		return nil, errors.New("unknown secrets_backend '" + cfg.SecretsBackend + "'")
+	}
+}
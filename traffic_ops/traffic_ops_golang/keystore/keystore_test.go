@@ -0,0 +1,66 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"testing"
+
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/config"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	cases := []struct {
+		name     string
+		cfg      *config.Config
+		wantType string
+		wantErr  bool
+	}{
+		{name: "empty backend defaults to riak", cfg: &config.Config{}, wantType: "*keystore.RiakStore"},
+		{name: "explicit riak", cfg: &config.Config{SecretsBackend: BackendRiak}, wantType: "*keystore.RiakStore"},
+		{name: "memory", cfg: &config.Config{SecretsBackend: BackendMemory}, wantType: "*keystore.MemoryStore"},
+		{name: "vault without config errors", cfg: &config.Config{SecretsBackend: BackendVault}, wantErr: true},
+		{name: "unknown backend errors", cfg: &config.Config{SecretsBackend: "nope"}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			store, err := NewFromConfig(c.cfg)
+			if c.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch c.wantType {
+			case "*keystore.RiakStore":
+				if _, ok := store.(*RiakStore); !ok {
+					t.Errorf("store = %T, want *RiakStore", store)
+				}
+			case "*keystore.MemoryStore":
+				if _, ok := store.(*MemoryStore); !ok {
+					t.Errorf("store = %T, want *MemoryStore", store)
+				}
+			}
+		})
+	}
+}
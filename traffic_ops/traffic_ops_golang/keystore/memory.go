@@ -0,0 +1,108 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+)
+
+// MemoryStore is an in-memory Store, for unit tests that need a Store but
+// shouldn't require a Riak or Vault instance.
+type MemoryStore struct {
+	mu      sync.Mutex
+	dnssec  map[string]tc.DNSSECKeys
+	urlsig  map[string]tc.URLSigKeys
+	sslkeys map[string]tc.SSLKeys
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		dnssec:  map[string]tc.DNSSECKeys{},
+		urlsig:  map[string]tc.URLSigKeys{},
+		sslkeys: map[string]tc.SSLKeys{},
+	}
+}
+
+func (s *MemoryStore) GetDNSSECKeys(tx *sql.Tx, cdnName string) (tc.DNSSECKeys, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.dnssec[cdnName]
+	return keys, ok, nil
+}
+
+func (s *MemoryStore) PutDNSSECKeys(tx *sql.Tx, cdnName string, keys tc.DNSSECKeys) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dnssec[cdnName] = keys
+	return nil
+}
+
+func (s *MemoryStore) DeleteDNSSECKeys(tx *sql.Tx, cdnName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.dnssec, cdnName)
+	return nil
+}
+
+func (s *MemoryStore) GetURLSigKeys(tx *sql.Tx, dsName string) (tc.URLSigKeys, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.urlsig[dsName]
+	return keys, ok, nil
+}
+
+func (s *MemoryStore) PutURLSigKeys(tx *sql.Tx, dsName string, keys tc.URLSigKeys) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.urlsig[dsName] = keys
+	return nil
+}
+
+func (s *MemoryStore) DeleteURLSigKeys(tx *sql.Tx, dsName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.urlsig, dsName)
+	return nil
+}
+
+func (s *MemoryStore) GetSSLKeys(tx *sql.Tx, dsName string, version string) (tc.SSLKeys, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.sslkeys[dsName+"-"+version]
+	return keys, ok, nil
+}
+
+func (s *MemoryStore) PutSSLKeys(tx *sql.Tx, dsName string, version string, keys tc.SSLKeys) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sslkeys[dsName+"-"+version] = keys
+	return nil
+}
+
+func (s *MemoryStore) DeleteSSLKeys(tx *sql.Tx, dsName string, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sslkeys, dsName+"-"+version)
+	return nil
+}
@@ -0,0 +1,108 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"testing"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+)
+
+func TestMemoryStoreDNSSECKeys(t *testing.T) {
+	s := NewMemoryStore()
+
+	if _, exists, err := s.GetDNSSECKeys(nil, "cdn1"); err != nil || exists {
+		t.Fatalf("GetDNSSECKeys on empty store = (_, %v, %v), want (_, false, nil)", exists, err)
+	}
+
+	keys := tc.DNSSECKeys{"cdn1": tc.DNSSECKeySet{ZSK: []tc.DNSSECKey{{Name: "cdn1"}}}}
+	if err := s.PutDNSSECKeys(nil, "cdn1", keys); err != nil {
+		t.Fatalf("PutDNSSECKeys: unexpected error: %v", err)
+	}
+
+	got, exists, err := s.GetDNSSECKeys(nil, "cdn1")
+	if err != nil {
+		t.Fatalf("GetDNSSECKeys: unexpected error: %v", err)
+	}
+	if !exists {
+		t.Fatal("GetDNSSECKeys: exists = false, want true")
+	}
+	if len(got["cdn1"].ZSK) != 1 || got["cdn1"].ZSK[0].Name != "cdn1" {
+		t.Errorf("GetDNSSECKeys = %+v, want the keys just put", got)
+	}
+
+	if err := s.DeleteDNSSECKeys(nil, "cdn1"); err != nil {
+		t.Fatalf("DeleteDNSSECKeys: unexpected error: %v", err)
+	}
+	if _, exists, err := s.GetDNSSECKeys(nil, "cdn1"); err != nil || exists {
+		t.Fatalf("GetDNSSECKeys after delete = (_, %v, %v), want (_, false, nil)", exists, err)
+	}
+}
+
+func TestMemoryStoreURLSigKeys(t *testing.T) {
+	s := NewMemoryStore()
+
+	keys := tc.URLSigKeys{"key0": []byte("secret")}
+	if err := s.PutURLSigKeys(nil, "ds1", keys); err != nil {
+		t.Fatalf("PutURLSigKeys: unexpected error: %v", err)
+	}
+
+	got, exists, err := s.GetURLSigKeys(nil, "ds1")
+	if err != nil || !exists {
+		t.Fatalf("GetURLSigKeys = (_, %v, %v), want (_, true, nil)", exists, err)
+	}
+	if string(got["key0"]) != "secret" {
+		t.Errorf("GetURLSigKeys = %+v, want key0=secret", got)
+	}
+
+	if err := s.DeleteURLSigKeys(nil, "ds1"); err != nil {
+		t.Fatalf("DeleteURLSigKeys: unexpected error: %v", err)
+	}
+	if _, exists, _ := s.GetURLSigKeys(nil, "ds1"); exists {
+		t.Error("GetURLSigKeys after delete: exists = true, want false")
+	}
+}
+
+func TestMemoryStoreSSLKeys(t *testing.T) {
+	s := NewMemoryStore()
+
+	keys := tc.SSLKeys{DeliveryService: "ds1", Version: 1}
+	if err := s.PutSSLKeys(nil, "ds1", "1", keys); err != nil {
+		t.Fatalf("PutSSLKeys: unexpected error: %v", err)
+	}
+
+	got, exists, err := s.GetSSLKeys(nil, "ds1", "1")
+	if err != nil || !exists {
+		t.Fatalf("GetSSLKeys = (_, %v, %v), want (_, true, nil)", exists, err)
+	}
+	if got.Version != 1 {
+		t.Errorf("GetSSLKeys = %+v, want Version 1", got)
+	}
+	if _, exists, _ := s.GetSSLKeys(nil, "ds1", "2"); exists {
+		t.Error("GetSSLKeys for a different version: exists = true, want false")
+	}
+
+	if err := s.DeleteSSLKeys(nil, "ds1", "1"); err != nil {
+		t.Fatalf("DeleteSSLKeys: unexpected error: %v", err)
+	}
+	if _, exists, _ := s.GetSSLKeys(nil, "ds1", "1"); exists {
+		t.Error("GetSSLKeys after delete: exists = true, want false")
+	}
+}
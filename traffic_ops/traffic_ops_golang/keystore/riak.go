@@ -0,0 +1,99 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/riaksvc"
+)
+
+// RiakStore is the Store backed by Riak, which is the only backend
+// supported before this package existed. It's a thin adapter over riaksvc.
+type RiakStore struct {
+	AuthOptions *riaksvc.AuthOptions
+}
+
+// NewRiakStore returns a Store that reads and writes keys in Riak using
+// authOptions.
+func NewRiakStore(authOptions *riaksvc.AuthOptions) *RiakStore {
+	return &RiakStore{AuthOptions: authOptions}
+}
+
+func (s *RiakStore) GetDNSSECKeys(tx *sql.Tx, cdnName string) (tc.DNSSECKeys, bool, error) {
+	return riaksvc.GetDNSSECKeys(cdnName, tx, s.AuthOptions)
+}
+
+func (s *RiakStore) PutDNSSECKeys(tx *sql.Tx, cdnName string, keys tc.DNSSECKeys) error {
+	return riaksvc.PutDNSSECKeys(keys, cdnName, tx, s.AuthOptions)
+}
+
+func (s *RiakStore) DeleteDNSSECKeys(tx *sql.Tx, cdnName string) error {
+	riakCluster, err := riaksvc.GetRiakClusterTx(tx, s.AuthOptions)
+	if err != nil {
+		return err
+	}
+	if err := riakCluster.Start(); err != nil {
+		return err
+	}
+	defer riaksvc.StopCluster(riakCluster)
+	return riaksvc.DeleteObject(cdnName, riaksvc.DNSSECKeyType, riakCluster)
+}
+
+func (s *RiakStore) GetURLSigKeys(tx *sql.Tx, dsName string) (tc.URLSigKeys, bool, error) {
+	return riaksvc.GetURLSigKeys(tx, s.AuthOptions, dsName)
+}
+
+func (s *RiakStore) PutURLSigKeys(tx *sql.Tx, dsName string, keys tc.URLSigKeys) error {
+	return riaksvc.PutURLSigKeys(tx, s.AuthOptions, dsName, keys)
+}
+
+func (s *RiakStore) DeleteURLSigKeys(tx *sql.Tx, dsName string) error {
+	riakCluster, err := riaksvc.GetRiakClusterTx(tx, s.AuthOptions)
+	if err != nil {
+		return err
+	}
+	if err := riakCluster.Start(); err != nil {
+		return err
+	}
+	defer riaksvc.StopCluster(riakCluster)
+	return riaksvc.DeleteObject(dsName, riaksvc.URLSigKeyType, riakCluster)
+}
+
+func (s *RiakStore) GetSSLKeys(tx *sql.Tx, dsName string, version string) (tc.SSLKeys, bool, error) {
+	return riaksvc.GetDeliveryServiceSSLKeys(dsName, version, tx, s.AuthOptions)
+}
+
+func (s *RiakStore) PutSSLKeys(tx *sql.Tx, dsName string, version string, keys tc.SSLKeys) error {
+	return riaksvc.PutDeliveryServiceSSLKeysObj(keys, tx, s.AuthOptions)
+}
+
+func (s *RiakStore) DeleteSSLKeys(tx *sql.Tx, dsName string, version string) error {
+	riakCluster, err := riaksvc.GetRiakClusterTx(tx, s.AuthOptions)
+	if err != nil {
+		return err
+	}
+	if err := riakCluster.Start(); err != nil {
+		return err
+	}
+	defer riaksvc.StopCluster(riakCluster)
+	return riaksvc.DeleteObject(dsName+"-"+version, riaksvc.SSLKeyType, riakCluster)
+}
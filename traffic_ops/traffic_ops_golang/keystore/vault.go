@@ -0,0 +1,142 @@
+package keystore
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	"github.com/apache/trafficcontrol/lib/go-tc"
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/config"
+
+	vaultapi "github.com/hashicorp/vault/api"
+)
+
+// VaultStore is the Store backed by a KV v2 secrets engine in Vault, for
+// operators who don't want to run Riak.
+type VaultStore struct {
+	client *vaultapi.Client
+	mount  string
+}
+
+// NewVaultStore builds a VaultStore from cfg, which carries the Vault
+// address, token, and KV mount path.
+func NewVaultStore(cfg *config.VaultConfig) (*VaultStore, error) {
+	if cfg == nil {
+		return nil, errors.New("vault config is required when secrets_backend is 'vault'")
+	}
+	vc := vaultapi.DefaultConfig()
+	vc.Address = cfg.Address
+	client, err := vaultapi.NewClient(vc)
+	if err != nil {
+		return nil, errors.New("creating vault client: " + err.Error())
+	}
+	client.SetToken(cfg.Token)
+	return &VaultStore{client: client, mount: cfg.Mount}, nil
+}
+
+func (s *VaultStore) path(kind string, name string) string {
+	return s.mount + "/data/" + kind + "/" + name
+}
+
+func (s *VaultStore) get(path string, out interface{}) (bool, error) {
+	secret, err := s.client.Logical().Read(path)
+	if err != nil {
+		return false, errors.New("reading vault secret '" + path + "': " + err.Error())
+	}
+	if secret == nil || secret.Data["data"] == nil {
+		return false, nil
+	}
+	raw, err := json.Marshal(secret.Data["data"])
+	if err != nil {
+		return false, errors.New("marshaling vault secret '" + path + "': " + err.Error())
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return false, errors.New("unmarshaling vault secret '" + path + "': " + err.Error())
+	}
+	return true, nil
+}
+
+func (s *VaultStore) put(path string, val interface{}) error {
+	raw, err := json.Marshal(val)
+	if err != nil {
+		return errors.New("marshaling value for vault secret '" + path + "': " + err.Error())
+	}
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return errors.New("unmarshaling value for vault secret '" + path + "': " + err.Error())
+	}
+	_, err = s.client.Logical().Write(path, map[string]interface{}{"data": data})
+	if err != nil {
+		return errors.New("writing vault secret '" + path + "': " + err.Error())
+	}
+	return nil
+}
+
+func (s *VaultStore) delete(path string) error {
+	_, err := s.client.Logical().Delete(path)
+	if err != nil {
+		return errors.New("deleting vault secret '" + path + "': " + err.Error())
+	}
+	return nil
+}
+
+func (s *VaultStore) GetDNSSECKeys(tx *sql.Tx, cdnName string) (tc.DNSSECKeys, bool, error) {
+	keys := tc.DNSSECKeys{}
+	exists, err := s.get(s.path("dnssec", cdnName), &keys)
+	return keys, exists, err
+}
+
+func (s *VaultStore) PutDNSSECKeys(tx *sql.Tx, cdnName string, keys tc.DNSSECKeys) error {
+	return s.put(s.path("dnssec", cdnName), keys)
+}
+
+func (s *VaultStore) DeleteDNSSECKeys(tx *sql.Tx, cdnName string) error {
+	return s.delete(s.path("dnssec", cdnName))
+}
+
+func (s *VaultStore) GetURLSigKeys(tx *sql.Tx, dsName string) (tc.URLSigKeys, bool, error) {
+	keys := tc.URLSigKeys{}
+	exists, err := s.get(s.path("urlsig", dsName), &keys)
+	return keys, exists, err
+}
+
+func (s *VaultStore) PutURLSigKeys(tx *sql.Tx, dsName string, keys tc.URLSigKeys) error {
+	return s.put(s.path("urlsig", dsName), keys)
+}
+
+func (s *VaultStore) DeleteURLSigKeys(tx *sql.Tx, dsName string) error {
+	return s.delete(s.path("urlsig", dsName))
+}
+
+func (s *VaultStore) GetSSLKeys(tx *sql.Tx, dsName string, version string) (tc.SSLKeys, bool, error) {
+	keys := tc.SSLKeys{}
+	exists, err := s.get(s.path("sslkeys", dsName+"-"+version), &keys)
+	return keys, exists, err
+}
+
+func (s *VaultStore) PutSSLKeys(tx *sql.Tx, dsName string, version string, keys tc.SSLKeys) error {
+	return s.put(s.path("sslkeys", dsName+"-"+version), keys)
+}
+
+func (s *VaultStore) DeleteSSLKeys(tx *sql.Tx, dsName string, version string) error {
+	return s.delete(s.path("sslkeys", dsName+"-"+version))
+}
@@ -0,0 +1,42 @@
+package main
+
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+import (
+	"net/http"
+
+	"github.com/apache/trafficcontrol/traffic_ops/traffic_ops_golang/cdn"
+)
+
+// Route associates an HTTP method and a mux-style path pattern (e.g.
+// "/cdns/{name}/dnsseckeys/refresh") with the handler that serves it.
+type Route struct {
+	Method  string
+	Path    string
+	Handler http.HandlerFunc
+}
+
+// Routes returns every API route this server handles.
+func Routes() []Route {
+	return []Route{
+		{http.MethodPost, "/cdns/{name}/dnsseckeys/refresh", cdn.RefreshDNSSECKeys},
+		{http.MethodGet, "/cdns/{name}/dnsseckeys/zonefile", cdn.GetDNSSECZoneFile},
+	}
+}